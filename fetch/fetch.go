@@ -0,0 +1,122 @@
+// Package fetch discovers the YouTube playlist items that make up the Go course.
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VideoItem is a single playlist entry as returned by the YouTube Data API.
+type VideoItem struct {
+	Snippet struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Thumbnails  struct {
+			Standard struct {
+				URL string `json:"url"`
+			} `json:"standard"`
+		} `json:"thumbnails"`
+		Position int `json:"position"`
+	} `json:"snippet"`
+	ContentDetails struct {
+		VideoId          string `json:"videoId"`
+		VideoPublishedAt string `json:"videoPublishedAt"`
+	} `json:"contentDetails"`
+}
+
+// PlaylistItems is a page of the YouTube playlistItems API response.
+type PlaylistItems struct {
+	Items         []VideoItem `json:"items"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+const (
+	defaultBaseURL    = "https://www.googleapis.com/youtube/v3/playlistItems"
+	defaultPlaylistID = "PLoILbKo9rG3skRCj37Kn5Zj803hhiuRK6"
+)
+
+var part = []string{"snippet", "contentDetails"} // Add more if required
+
+// Fetcher fetches playlist items from the YouTube Data API.
+type Fetcher struct {
+	BaseURL    string
+	PlaylistID string
+}
+
+// NewFetcher builds a Fetcher for the Go course playlist.
+func NewFetcher() *Fetcher {
+	return &Fetcher{
+		BaseURL:    defaultBaseURL,
+		PlaylistID: defaultPlaylistID,
+	}
+}
+
+// GetPlaylistItems fetches every item in the configured playlist, following pagination.
+func (f *Fetcher) GetPlaylistItems(ctx context.Context) ([]VideoItem, error) {
+	apiKey, ok := os.LookupEnv("YOUTUBE_API_KEY")
+	if !ok {
+		return nil, fmt.Errorf("YOUTUBE_API_KEY environment variable is not set")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("YOUTUBE_API_KEY environment variable is empty")
+	}
+
+	var videoItems []VideoItem
+	url := f.BaseURL + "?part=" + strings.Join(part, ",") + "&playlistId=" + f.PlaylistID + "&key=" + apiKey + "&maxResults=50"
+	pageToken := ""
+
+	for {
+		var playlistItems PlaylistItems
+
+		reqUrl := url
+		if pageToken != "" {
+			reqUrl += "&pageToken=" + pageToken
+		}
+
+		// Create a timeout context
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+
+		// Make a GET request to the YouTube API
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("Failed to create request: %w", err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("Failed to make request to YouTube API: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			cancel()
+			return nil, fmt.Errorf("Received non-200 response from YouTube API: %s", resp.Status)
+		}
+
+		// Decode the JSON response into the PlaylistItems struct
+		if err := json.NewDecoder(resp.Body).Decode(&playlistItems); err != nil {
+			cancel()
+			return nil, fmt.Errorf("Failed to decode JSON response: %w", err)
+		} else {
+			cancel()
+		}
+
+		// Append the items to the videoItems slice
+		videoItems = append(videoItems, playlistItems.Items...)
+
+		if playlistItems.NextPageToken == "" {
+			break // No more pages to fetch
+		} else {
+			pageToken = playlistItems.NextPageToken // Update the page token for the next iteration
+		}
+	}
+
+	return videoItems, nil
+}
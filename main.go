@@ -2,324 +2,204 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
 	"google.golang.org/genai"
-)
-
-type VideoItem struct {
-	Snippet struct {
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Thumbnails  struct {
-			Standard struct {
-				URL string `json:"url"`
-			} `json:"standard"`
-		} `json:"thumbnails"`
-		Position int `json:"position"`
-	} `json:"snippet"`
-	ContentDetails struct {
-		VideoId          string `json:"videoId"`
-		VideoPublishedAt string `json:"videoPublishedAt"`
-	} `json:"contentDetails"`
-}
 
-type PlaylistItems struct {
-	Items         []VideoItem `json:"items"`
-	NextPageToken string      `json:"nextPageToken"`
-}
+	"github.com/Ar4v1nd/go-course-modernizer/fetch"
+	"github.com/Ar4v1nd/go-course-modernizer/process"
+	"github.com/Ar4v1nd/go-course-modernizer/releasenotes"
+	"github.com/Ar4v1nd/go-course-modernizer/store"
+)
 
-var baseUrl = "https://www.googleapis.com/youtube/v3/playlistItems"
-var part = []string{"snippet", "contentDetails"} // Add more if required
-var playlistId = "PLoILbKo9rG3skRCj37Kn5Zj803hhiuRK6"
-var releaseNotes []*genai.File
+const (
+	releaseNotesDir      = "./releasenote"
+	releaseNotesCacheDir = "./cache/releasenotes"
+	markdownDir          = "markdown"
+	stateStorePath       = "./ytmod_state.json"
+)
 
-func uploadReleaseNotes(ctx context.Context, logger *slog.Logger, client *genai.Client) error {
-	logger.Info("Uploading release notes to Gemini")
+// uploadReleaseNotes prefers auto-discovering and caching Go release notes from go.dev,
+// falling back to the locally curated PDF directory when the network is unavailable.
+func uploadReleaseNotes(ctx context.Context, logger *slog.Logger, client *genai.Client, proc *process.GeminiProcessor) error {
+	versions, err := releasenotes.Discover(ctx, releasenotes.DefaultFloor)
+	if err != nil {
+		logger.Warn("Could not discover Go release notes from go.dev, falling back to local PDFs", "error", err)
+		return proc.UploadReleaseNotes(ctx, releaseNotesDir)
+	}
 
-	releaseNotesDir := "./releasenote"
-	// Walk the release notes directory
-	err := filepath.Walk(releaseNotesDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			logger.Error("Error accessing path", "path", path, "error", err)
-			return fmt.Errorf("Error accessing path %q: %v", path, err)
-		}
-		if info.IsDir() || !strings.HasSuffix(info.Name(), ".pdf") {
-			return nil // Skip directories and non-pdf files
-		}
-		// Upload the release notes to Gemini
-		f, err := client.Files.UploadFromPath(ctx, path, &genai.UploadFileConfig{
-			MIMEType: "application/pdf",
-		})
+	var notes []releasenotes.Note
+	for _, version := range versions {
+		note, err := releasenotes.FetchAndCache(ctx, version, releaseNotesCacheDir)
 		if err != nil {
-			logger.Error("Error uploading release notes file to Gemini", "file", path, "error", err)
-			return fmt.Errorf("Error uploading release notes file from %q to Gemini: %v", path, err)
+			logger.Error("Failed to fetch release notes, skipping version", "version", version, "error", err)
+			continue
 		}
-		logger.Info("Successfully uploaded release notes file", "file", path)
-		releaseNotes = append(releaseNotes, f)
-		return nil
-	})
+		notes = append(notes, note)
+	}
+	if len(notes) == 0 {
+		logger.Warn("No Go release notes could be fetched from go.dev, falling back to local PDFs")
+		return proc.UploadReleaseNotes(ctx, releaseNotesDir)
+	}
+
+	manifestPath := filepath.Join(releaseNotesCacheDir, "uploaded.json")
+	files, err := releasenotes.UploadAll(ctx, client.Files, logger, notes, manifestPath)
 	if err != nil {
-		logger.Error("Failed to upload release notes", "error", err)
-		return fmt.Errorf("Failed to upload release notes: %w", err)
+		return fmt.Errorf("Failed to upload release notes to Gemini: %w", err)
 	}
-	logger.Info("Successfully uploaded all release notes", "count", len(releaseNotes))
+	proc.SetReleaseNotes(files)
 	return nil
 }
 
-func processVideo(ctx context.Context, wg *sync.WaitGroup, logger *slog.Logger, client *genai.Client, limits chan struct{}, results chan<- map[string]string, item VideoItem) error {
+// processVideo runs the summarize-then-validate pipeline for a single video, skipping the
+// Processor calls when the state store already holds an up-to-date, validated result (the
+// Markdown and JSON output are still rewritten from that cached result).
+func processVideo(ctx context.Context, wg *sync.WaitGroup, logger *slog.Logger, proc process.Processor, limits chan struct{}, results chan<- store.VideoResult, item fetch.VideoItem, st *store.Store, force bool) error {
 	defer wg.Done()
 
-	limits <- struct{}{}        // Acquire a limit
-	defer func() { <-limits }() // Release the limit when done
-
-	summarizerPrompt := `
-	You are an expert in Go programming.
-
-	You will be given a YouTube video URL of a Go programming course recorded with Go version 1.15.
-	
-	Your task is to summarize the video by following these guidelines:
-	1. Dissect the video content into distinct chapters based on the topics covered.
-	2. For each chapter, summarize the key concepts and best practices as concise bullet points:
-		- Include relevant Go code snippets.
-		- Do not include video timestamps or references to specific moments in the video.
-	3. Return your response in the following strict **Markdown format only**, with no additional text:
-	# ` + item.Snippet.Title + `
-
-	## Summary
-	(A brief overview of the video content.)
-
-	## Key Points
-	(A list of chapters with their summaries in concise bullet points. Include relevant Go code snippets or examples.)
-	`
-
-	logger.Info("Sending request to Gemini for video summarization", "videoId", item.ContentDetails.VideoId, "title", item.Snippet.Title)
-
-	parts := []*genai.Part{
-		genai.NewPartFromURI(fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.ContentDetails.VideoId), "video/mp4"),
-		genai.NewPartFromText(summarizerPrompt),
-	}
-
-	contents := []*genai.Content{
-		genai.NewContentFromParts(parts, genai.RoleUser),
-	}
+	videoId := item.ContentDetails.VideoId
+	hash := store.PromptHash(process.SummarizerPromptTemplate, process.ValidatorPromptTemplate)
 
-	temperature := float32(0.1)
-	thinkingBudget := int32(-1) // Set to -1 for dynamic thinking
-	response, err := client.Models.GenerateContent(
-		ctx,
-		"gemini-2.5-flash",
-		contents,
-		&genai.GenerateContentConfig{
-			Temperature:        &temperature,
-			ResponseModalities: []string{"TEXT"},
-			ThinkingConfig: &genai.ThinkingConfig{
-				ThinkingBudget: &thinkingBudget,
-			},
-		},
-	)
-	if err != nil {
-		logger.Error("Failed to summarize video using Gemini", "videoId", item.ContentDetails.VideoId, "error", err)
-		return fmt.Errorf("Failed to summarize video using Gemini: %w", err)
+	if err := st.Update(videoId, func(state *store.VideoState) {
+		state.Stages[store.StageFetched] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		logger.Error("Failed to update state store", "videoId", videoId, "error", err)
 	}
 
-	logger.Info("Received summarization response from Gemini", "videoId", item.ContentDetails.VideoId, "response", len(response.Text()))
-
-	usageMetadata, err := json.MarshalIndent(response.UsageMetadata, "", "  ")
-	if err != nil {
-		logger.Error("Failed to marshal usage metadata", "videoId", item.ContentDetails.VideoId, "error", err)
-	} else {
-		// Try to unmarshal usageMetadata into a map to access token counts
-		var metaMap map[string]any
-		if err := json.Unmarshal(usageMetadata, &metaMap); err == nil {
-			if thoughts, ok := metaMap["thoughtsTokenCount"]; ok {
-				logger.Info("Thoughts tokens", "count", thoughts)
-			}
-			if candidates, ok := metaMap["candidatesTokenCount"]; ok {
-				logger.Info("Output tokens", "count", candidates)
+	if !force {
+		if state, ok := st.Get(videoId); ok &&
+			state.PublishedAt == item.ContentDetails.VideoPublishedAt &&
+			state.PromptHash == hash &&
+			state.ModelVersion == process.DefaultModel {
+			if _, validated := state.Stages[store.StageValidated]; validated && state.Validated != "" {
+				logger.Info("Skipping video, cached state is up to date", "videoId", videoId, "title", item.Snippet.Title)
+				result := buildVideoResult(item, state.Validated, hash,
+					state.Stages[store.StageSummarized].TokenCount, state.Stages[store.StageValidated].TokenCount)
+				if err := writeVideoOutput(logger, st, item, state.Validated, result); err != nil {
+					return err
+				}
+				results <- result
+				return nil
 			}
-		} else {
-			logger.Info("Usage metadata is not a valid JSON", "usageMetadata", string(usageMetadata))
 		}
 	}
 
-	// Optional: Sleep for a short duration to avoid hitting API rate limits
-	// time.Sleep(1 * time.Minute)
-
-	validatorPrompt := `
-	You are a technical content editor who is an expert in Go programming.
-
-	You will be given the summary and key points in Markdown format derived from a Go programming course recorded with Go version 1.15.
-
-	Your task is to evaluate each key point present under the "Key Points" section of the Markdown using the provided Go release notes PDF files (from versions 1.16 to 1.24) by following these guidelines:
-	1. Determine if every key point is **still valid and accurate** in the latest Go version (1.24) based on the release notes.
-		- **Only** consider the following sections in the release note PDF files while evaluating the key points: "Changes to the language", "Tools" and "Standard library". Ignore any other sections.
-		- Do **not** evaluate key points expressing opinions, philosophies, or general design principles.
-		- Only focus on factual key points about Go syntax, behavior, deprecation, tooling, etc.
-	2. For any key point that is no longer valid or accurate:
-		- Briefly explain what has changed in the latest Go version that affects the key point.
-		- Cite the **first Go version** where the change was introduced using a numbered format like [1], [2], etc.
-		- Do not cite a Go version unless it is directly relevant to the key point. Also, do not cite multiple versions for the same change (choose the most relevant one).
-		- Provide updated code snippets if the original code is outdated.
-	3. Do not use any prior knowledge about Go. Only base your answers on the provided release note PDFs.
-	4. Return your response in the following strict **Markdown format only**, with no additional text:
-	# ` + item.Snippet.Title + `
-
-	## Summary
-	(Summary passed to you as input, do not change it.)
-
-	## Key Points
-	(Key points passed to you as input, do not change them.)
-
-	## What's New
-	(A list of changes found in the key points based on the release notes, with each change cited to the relevant Go version in [x] numbered format.)
-
-	## Updated Code Snippets
-	(If any code snippets in the key points were outdated, provide the updated versions here. If no updated code snippets are needed, omit this section entirely.)
-
-	## Citations
-	(A list of Go version release notes cited in the format [1], [2], etc. For example:
-	- [1] Go version 1.16
-	- [2] Go version 1.17
-	)
-
-	Here are the summary and key points in Markdown format you need to evaluate:
-	` + response.Text()
-
-	parts = []*genai.Part{}
-	for i, file := range releaseNotes {
-		parts = append(parts, genai.NewPartFromText(fmt.Sprintf("[%d] %s", i+1, file.Name)), genai.NewPartFromURI(file.URI, file.MIMEType))
-	}
-	parts = append(parts, genai.NewPartFromText(validatorPrompt))
-
-	contents = []*genai.Content{
-		genai.NewContentFromParts(parts, genai.RoleUser),
-	}
+	limits <- struct{}{}        // Acquire a limit
+	defer func() { <-limits }() // Release the limit when done
 
-	temperature = float32(0.0)    // Set temperature to 0 for validation
-	thinkingBudget = int32(24576) // Set a high thinking budget for thorough validation
-	response, err = client.Models.GenerateContent(
-		ctx,
-		"gemini-2.5-flash",
-		contents,
-		&genai.GenerateContentConfig{
-			Temperature:        &temperature,
-			ResponseModalities: []string{"TEXT"},
-			ThinkingConfig: &genai.ThinkingConfig{
-				ThinkingBudget: &thinkingBudget,
-			},
-		},
-	)
+	summary, validated, err := process.Run(ctx, proc, item)
 	if err != nil {
-		logger.Error("Failed to validate using Gemini", "videoId", item.ContentDetails.VideoId, "error", err)
-		return fmt.Errorf("Failed to validate using Gemini: %w", err)
+		return err
 	}
 
-	logger.Info("Received validation response from Gemini", "videoId", item.ContentDetails.VideoId, "response", len(response.Text()))
-
-	usageMetadata, err = json.MarshalIndent(response.UsageMetadata, "", "  ")
-	if err != nil {
-		logger.Error("Failed to marshal usage metadata", "videoId", item.ContentDetails.VideoId, "error", err)
-	} else {
-		// Try to unmarshal usageMetadata into a map to access token counts
-		var metaMap map[string]any
-		if err := json.Unmarshal(usageMetadata, &metaMap); err == nil {
-			if thoughts, ok := metaMap["thoughtsTokenCount"]; ok {
-				logger.Info("Thoughts tokens", "count", thoughts)
-			}
-			if candidates, ok := metaMap["candidatesTokenCount"]; ok {
-				logger.Info("Output tokens", "count", candidates)
-			}
-		} else {
-			logger.Info("Usage metadata is not a valid JSON", "usageMetadata", string(usageMetadata))
-		}
+	if err := st.Update(videoId, func(state *store.VideoState) {
+		state.PublishedAt = item.ContentDetails.VideoPublishedAt
+		state.PromptHash = hash
+		state.ModelVersion = process.DefaultModel
+		state.Summary = summary.Content
+		state.Validated = validated.Content
+		now := time.Now()
+		state.Stages[store.StageSummarized] = store.StageRecord{CompletedAt: now, TokenCount: summary.TokenCount}
+		state.Stages[store.StageValidated] = store.StageRecord{CompletedAt: now, TokenCount: validated.TokenCount}
+	}); err != nil {
+		logger.Error("Failed to update state store", "videoId", videoId, "error", err)
 	}
 
-	results <- map[string]string{
-		item.Snippet.Title: response.Text(),
+	result := buildVideoResult(item, validated.Content, hash, summary.TokenCount, validated.TokenCount)
+	if err := writeVideoOutput(logger, st, item, validated.Content, result); err != nil {
+		return err
 	}
 
+	results <- result
 	return nil
 }
 
-func getPlaylistItems(ctx context.Context) ([]VideoItem, error) {
-	apiKey, ok := os.LookupEnv("YOUTUBE_API_KEY")
-	if !ok {
-		return nil, fmt.Errorf("YOUTUBE_API_KEY environment variable is not set")
+// writeVideoOutput writes a video's Markdown file and its JSON sidecar, then records the
+// written stage in the state store.
+func writeVideoOutput(logger *slog.Logger, st *store.Store, item fetch.VideoItem, content string, result store.VideoResult) error {
+	if err := store.WriteMarkdown(markdownDir, item.Snippet.Title, content); err != nil {
+		logger.Error("Error writing summary to file", "title", item.Snippet.Title, "error", err)
+		return err
 	}
-	if apiKey == "" {
-		return nil, fmt.Errorf("YOUTUBE_API_KEY environment variable is empty")
+	if err := store.WriteVideoResultJSON(markdownDir, result); err != nil {
+		logger.Error("Error writing video result JSON", "videoId", item.ContentDetails.VideoId, "error", err)
+		return err
 	}
 
-	var videoItems []VideoItem
-	url := baseUrl + "?part=" + strings.Join(part, ",") + "&playlistId=" + playlistId + "&key=" + apiKey + "&maxResults=50"
-	pageToken := ""
-
-	for {
-		var playlistItems PlaylistItems
-
-		reqUrl := url
-		if pageToken != "" {
-			reqUrl += "&pageToken=" + pageToken
-		}
-
-		// Create a timeout context
-		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
-
-		// Make a GET request to the YouTube API
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqUrl, nil)
-		if err != nil {
-			cancel()
-			return nil, fmt.Errorf("Failed to create request: %w", err)
-		}
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			cancel()
-			return nil, fmt.Errorf("Failed to make request to YouTube API: %w", err)
-		}
-		defer resp.Body.Close()
+	videoId := item.ContentDetails.VideoId
+	if err := st.Update(videoId, func(state *store.VideoState) {
+		state.Stages[store.StageWritten] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		logger.Error("Failed to update state store", "videoId", videoId, "error", err)
+	}
+	return nil
+}
 
-		if resp.StatusCode != http.StatusOK {
-			cancel()
-			return nil, fmt.Errorf("Received non-200 response from YouTube API: %s", resp.Status)
-		}
+// buildVideoResult assembles the typed VideoResult for a video from its validated
+// Markdown content, parsing out citations and per-key-point validity.
+func buildVideoResult(item fetch.VideoItem, validatedContent, promptHash string, summarizerTokens, validatorTokens int32) store.VideoResult {
+	return store.ParseVideoResult(
+		validatedContent,
+		item.ContentDetails.VideoId,
+		item.Snippet.Title,
+		item.ContentDetails.VideoPublishedAt,
+		item.Snippet.Position,
+		process.DefaultModel,
+		promptHash,
+		summarizerTokens,
+		validatorTokens,
+		filepath.Join(markdownDir, store.SafeFilename(item.Snippet.Title)+".md"),
+	)
+}
 
-		// Decode the JSON response into the PlaylistItems struct
-		if err := json.NewDecoder(resp.Body).Decode(&playlistItems); err != nil {
-			cancel()
-			return nil, fmt.Errorf("Failed to decode JSON response: %w", err)
-		} else {
-			cancel()
-		}
+// runStatus prints the per-video pipeline state recorded in the state store and exits.
+func runStatus(logger *slog.Logger) {
+	st, err := store.New(stateStorePath)
+	if err != nil {
+		logger.Error("Error loading state store", "error", err)
+		os.Exit(-1)
+	}
 
-		// Append the items to the videoItems slice
-		videoItems = append(videoItems, playlistItems.Items...)
+	all := st.All()
+	videoIds := make([]string, 0, len(all))
+	for videoId := range all {
+		videoIds = append(videoIds, videoId)
+	}
+	sort.Strings(videoIds)
 
-		if playlistItems.NextPageToken == "" {
-			break // No more pages to fetch
-		} else {
-			pageToken = playlistItems.NextPageToken // Update the page token for the next iteration
+	for _, videoId := range videoIds {
+		state := all[videoId]
+		stages := make([]string, 0, len(state.Stages))
+		for stage := range state.Stages {
+			stages = append(stages, string(stage))
 		}
+		sort.Strings(stages)
+		fmt.Printf("%s\tpublishedAt=%s\tstages=%s\n", videoId, state.PublishedAt, strings.Join(stages, ","))
 	}
-
-	return videoItems, nil
 }
 
 func main() {
 	// Initialize logger
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
+	if len(os.Args) > 1 && os.Args[1] == "status" {
+		runStatus(logger)
+		return
+	}
+
+	force := flag.Bool("force", false, "Reprocess every video, ignoring any cached pipeline state")
+	forceDownload := flag.Bool("force-download", false, "Always summarize from a yt-dlp download instead of Gemini's YouTube URI ingest")
+	flag.Parse()
+
 	if err := godotenv.Load(); err != nil {
 		logger.Error("Error loading .env file", "error", err)
 		os.Exit(-1)
@@ -330,7 +210,7 @@ func main() {
 	ctx := context.Background()
 
 	// Fetch playlist items from YouTube API
-	playlistItems, err := getPlaylistItems(ctx)
+	playlistItems, err := fetch.NewFetcher().GetPlaylistItems(ctx)
 	if err != nil {
 		logger.Error("Error fetching playlist items", "error", err)
 		os.Exit(-1)
@@ -357,39 +237,39 @@ func main() {
 		os.Exit(-1)
 	}
 
+	proc := process.NewGeminiProcessor(client, logger, process.DefaultModel)
+	proc.SetForceDownload(*forceDownload)
+
 	// Upload release notes to Gemini
-	if err := uploadReleaseNotes(ctx, logger, client); err != nil {
+	if err := uploadReleaseNotes(ctx, logger, client, proc); err != nil {
 		logger.Error("Error uploading release notes to Gemini", "error", err)
 		os.Exit(-1)
 	}
 
+	st, err := store.New(stateStorePath)
+	if err != nil {
+		logger.Error("Error loading state store", "error", err)
+		os.Exit(-1)
+	}
+
 	wg := new(sync.WaitGroup)
 	limits := make(chan struct{}, 5) // Limit to 5 concurrent request to handle Gemini API rate limits
-	results := make(chan map[string]string, len(playlistItems))
+	results := make(chan store.VideoResult, len(playlistItems))
 
 	for _, item := range playlistItems {
 		wg.Add(1)
-		go processVideo(ctx, wg, logger, client, limits, results, item)
+		go processVideo(ctx, wg, logger, proc, limits, results, item, st, *force)
 	}
 	wg.Wait()
 	close(results)
 
-	// Ensure the "markdown" directory exists
-	if err := os.MkdirAll("markdown", 0755); err != nil {
-		logger.Error("Error creating markdown directory", "error", err)
-		os.Exit(-1)
-	}
-
-	// Collect results
+	// Collect results and aggregate the combined index
+	var videoResults []store.VideoResult
 	for result := range results {
-		for title, summary := range result {
-			re := regexp.MustCompile(`/`) // Replace slashes in titles
-			title = re.ReplaceAllString(title, "_")
-			filePath := filepath.Join("markdown", fmt.Sprintf("%s.md", title))
-			err := os.WriteFile(filePath, []byte(summary), 0644)
-			if err != nil {
-				logger.Error("Error writing summary to file", "title", title, "error", err)
-			}
-		}
+		videoResults = append(videoResults, result)
+	}
+	if err := store.WriteIndex(markdownDir, videoResults); err != nil {
+		logger.Error("Error writing markdown index", "error", err)
+		os.Exit(-1)
 	}
 }
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ar4v1nd/go-course-modernizer/fetch"
+	"github.com/Ar4v1nd/go-course-modernizer/process"
+	"github.com/Ar4v1nd/go-course-modernizer/store"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// chdirToTemp points the working directory at a scratch dir so processVideo's markdown/JSON
+// writes (which use the package-level markdownDir constant) don't touch the repo tree.
+func chdirToTemp(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd returned unexpected error: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("Chdir returned unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatalf("Chdir back to %q returned unexpected error: %v", wd, err)
+		}
+	})
+}
+
+func testItem() fetch.VideoItem {
+	var item fetch.VideoItem
+	item.ContentDetails.VideoId = "abc123"
+	item.ContentDetails.VideoPublishedAt = "2021-01-01T00:00:00Z"
+	item.Snippet.Title = "Intro to Goroutines"
+	return item
+}
+
+func neverCalledMock(t *testing.T) *process.MockProcessor {
+	return &process.MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (process.Result, error) {
+			t.Fatal("Summarize should not be called when cached state is reused")
+			return process.Result{}, nil
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (process.Result, error) {
+			t.Fatal("Validate should not be called when cached state is reused")
+			return process.Result{}, nil
+		},
+	}
+}
+
+func run(t *testing.T, proc process.Processor, item fetch.VideoItem, st *store.Store, force bool) {
+	t.Helper()
+	wg := new(sync.WaitGroup)
+	limits := make(chan struct{}, 1)
+	results := make(chan store.VideoResult, 1)
+
+	wg.Add(1)
+	if err := processVideo(context.Background(), wg, discardLogger(), proc, limits, results, item, st, force); err != nil {
+		t.Fatalf("processVideo returned unexpected error: %v", err)
+	}
+	close(results)
+}
+
+func TestProcessVideo_SkipsWhenCacheIsUpToDate(t *testing.T) {
+	chdirToTemp(t)
+
+	item := testItem()
+	st, err := store.New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.New returned unexpected error: %v", err)
+	}
+
+	hash := store.PromptHash(process.SummarizerPromptTemplate, process.ValidatorPromptTemplate)
+	if err := st.Update(item.ContentDetails.VideoId, func(state *store.VideoState) {
+		state.PublishedAt = item.ContentDetails.VideoPublishedAt
+		state.PromptHash = hash
+		state.ModelVersion = process.DefaultModel
+		state.Validated = "## Summary\ncached"
+		state.Stages[store.StageValidated] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	run(t, neverCalledMock(t), item, st, false)
+
+	state, _ := st.Get(item.ContentDetails.VideoId)
+	if _, ok := state.Stages[store.StageWritten]; !ok {
+		t.Error("expected StageWritten to be recorded even on a cache-hit rewrite")
+	}
+}
+
+func TestProcessVideo_ReprocessesWhenForced(t *testing.T) {
+	chdirToTemp(t)
+
+	item := testItem()
+	st, err := store.New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.New returned unexpected error: %v", err)
+	}
+
+	hash := store.PromptHash(process.SummarizerPromptTemplate, process.ValidatorPromptTemplate)
+	if err := st.Update(item.ContentDetails.VideoId, func(state *store.VideoState) {
+		state.PublishedAt = item.ContentDetails.VideoPublishedAt
+		state.PromptHash = hash
+		state.ModelVersion = process.DefaultModel
+		state.Validated = "## Summary\ncached"
+		state.Stages[store.StageValidated] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	called := false
+	mock := &process.MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (process.Result, error) {
+			called = true
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (process.Result, error) {
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+	}
+
+	run(t, mock, item, st, true)
+
+	if !called {
+		t.Error("expected Summarize to be called when force=true")
+	}
+}
+
+func TestProcessVideo_ReprocessesWhenPromptHashChanged(t *testing.T) {
+	chdirToTemp(t)
+
+	item := testItem()
+	st, err := store.New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.New returned unexpected error: %v", err)
+	}
+
+	if err := st.Update(item.ContentDetails.VideoId, func(state *store.VideoState) {
+		state.PublishedAt = item.ContentDetails.VideoPublishedAt
+		state.PromptHash = "stale-hash"
+		state.ModelVersion = process.DefaultModel
+		state.Validated = "## Summary\ncached"
+		state.Stages[store.StageValidated] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	called := false
+	mock := &process.MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (process.Result, error) {
+			called = true
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (process.Result, error) {
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+	}
+
+	run(t, mock, item, st, false)
+
+	if !called {
+		t.Error("expected Summarize to be called when the prompt hash no longer matches")
+	}
+}
+
+func TestProcessVideo_ReprocessesWhenPublishedAtChanged(t *testing.T) {
+	chdirToTemp(t)
+
+	item := testItem()
+	st, err := store.New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.New returned unexpected error: %v", err)
+	}
+
+	hash := store.PromptHash(process.SummarizerPromptTemplate, process.ValidatorPromptTemplate)
+	if err := st.Update(item.ContentDetails.VideoId, func(state *store.VideoState) {
+		state.PublishedAt = "2020-01-01T00:00:00Z"
+		state.PromptHash = hash
+		state.ModelVersion = process.DefaultModel
+		state.Validated = "## Summary\ncached"
+		state.Stages[store.StageValidated] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	called := false
+	mock := &process.MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (process.Result, error) {
+			called = true
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (process.Result, error) {
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+	}
+
+	run(t, mock, item, st, false)
+
+	if !called {
+		t.Error("expected Summarize to be called when the video's publish date changed")
+	}
+}
+
+func TestProcessVideo_ReprocessesWhenNotYetValidated(t *testing.T) {
+	chdirToTemp(t)
+
+	item := testItem()
+	st, err := store.New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("store.New returned unexpected error: %v", err)
+	}
+
+	hash := store.PromptHash(process.SummarizerPromptTemplate, process.ValidatorPromptTemplate)
+	if err := st.Update(item.ContentDetails.VideoId, func(state *store.VideoState) {
+		state.PublishedAt = item.ContentDetails.VideoPublishedAt
+		state.PromptHash = hash
+		state.ModelVersion = process.DefaultModel
+		state.Stages[store.StageSummarized] = store.StageRecord{CompletedAt: time.Now()}
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	called := false
+	mock := &process.MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (process.Result, error) {
+			called = true
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (process.Result, error) {
+			return process.Result{Content: "## Summary\nfresh", TokenCount: 1}, nil
+		},
+	}
+
+	run(t, mock, item, st, false)
+
+	if !called {
+		t.Error("expected Summarize to be called when the video hasn't been validated yet")
+	}
+}
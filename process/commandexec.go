@@ -0,0 +1,24 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+)
+
+// CommandExecutor runs an external command, abstracted so yt-dlp invocations can be
+// mocked in tests instead of shelling out for real.
+type CommandExecutor interface {
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// execCommandExecutor is the CommandExecutor used in production: it really runs the command.
+type execCommandExecutor struct{}
+
+// NewCommandExecutor returns the default CommandExecutor, which shells out via os/exec.
+func NewCommandExecutor() CommandExecutor {
+	return execCommandExecutor{}
+}
+
+func (execCommandExecutor) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
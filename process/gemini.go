@@ -0,0 +1,408 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ar4v1nd/go-course-modernizer/fetch"
+	"google.golang.org/genai"
+)
+
+const DefaultModel = "gemini-2.5-flash"
+const defaultDownloadCacheDir = "./cache/videos"
+
+// GeminiProcessor is the Processor implementation backed by the Gemini API.
+type GeminiProcessor struct {
+	client       *genai.Client
+	logger       *slog.Logger
+	model        string
+	releaseNotes []*genai.File
+
+	executor      CommandExecutor
+	cacheDir      string
+	forceDownload bool
+
+	limiter *RateLimiter
+}
+
+// NewGeminiProcessor builds a GeminiProcessor for the given client and model.
+func NewGeminiProcessor(client *genai.Client, logger *slog.Logger, model string) *GeminiProcessor {
+	return &GeminiProcessor{
+		client:   client,
+		logger:   logger,
+		model:    model,
+		executor: NewCommandExecutor(),
+		cacheDir: defaultDownloadCacheDir,
+		limiter:  RateLimiterFromEnv(),
+	}
+}
+
+// SetForceDownload makes Summarize always use the yt-dlp download fallback instead of
+// only falling back when Gemini's URI-based ingest fails.
+func (p *GeminiProcessor) SetForceDownload(force bool) {
+	p.forceDownload = force
+}
+
+// SetReleaseNotes injects release notes files already uploaded to Gemini by the caller
+// (for example by the releasenotes package), for use as citations during Validate.
+func (p *GeminiProcessor) SetReleaseNotes(files []*genai.File) {
+	p.releaseNotes = append(p.releaseNotes, files...)
+}
+
+// UploadReleaseNotes uploads every PDF under dir to Gemini so Validate can cite them.
+func (p *GeminiProcessor) UploadReleaseNotes(ctx context.Context, dir string) error {
+	p.logger.Info("Uploading release notes to Gemini")
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			p.logger.Error("Error accessing path", "path", path, "error", err)
+			return fmt.Errorf("Error accessing path %q: %v", path, err)
+		}
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".pdf") {
+			return nil // Skip directories and non-pdf files
+		}
+		f, err := p.client.Files.UploadFromPath(ctx, path, &genai.UploadFileConfig{
+			MIMEType: "application/pdf",
+		})
+		if err != nil {
+			p.logger.Error("Error uploading release notes file to Gemini", "file", path, "error", err)
+			return fmt.Errorf("Error uploading release notes file from %q to Gemini: %v", path, err)
+		}
+		p.logger.Info("Successfully uploaded release notes file", "file", path)
+		p.releaseNotes = append(p.releaseNotes, f)
+		return nil
+	})
+	if err != nil {
+		p.logger.Error("Failed to upload release notes", "error", err)
+		return fmt.Errorf("Failed to upload release notes: %w", err)
+	}
+	p.logger.Info("Successfully uploaded all release notes", "count", len(p.releaseNotes))
+	return nil
+}
+
+// Summarize tries Gemini's YouTube URI ingest first. That ingest silently degrades for
+// region/age-restricted videos, so an empty response or an error that looks like that
+// restriction (as opposed to a terminal account-level failure) triggers a fallback that
+// downloads the audio and auto-generated subtitles with yt-dlp instead.
+func (p *GeminiProcessor) Summarize(ctx context.Context, item fetch.VideoItem) (Result, error) {
+	if !p.forceDownload {
+		result, err := p.summarizeFromURI(ctx, item)
+		if err == nil && strings.TrimSpace(result.Content) != "" {
+			return result, nil
+		}
+		if err != nil {
+			if !isRestrictionLikeError(err) {
+				return Result{}, err
+			}
+			p.logger.Warn("URI-based summarization failed, falling back to yt-dlp download", "videoId", item.ContentDetails.VideoId, "error", err)
+		} else {
+			p.logger.Warn("URI-based summarization returned an empty response, falling back to yt-dlp download", "videoId", item.ContentDetails.VideoId)
+		}
+	}
+
+	return p.summarizeFromDownload(ctx, item)
+}
+
+// isRestrictionLikeError reports whether err from the URI-based ingest is worth retrying via
+// the yt-dlp download fallback. It excludes terminal account-level failures (bad API key,
+// quota exhaustion) that have nothing to do with region/age restriction and would just fail
+// the same way again after burning a download and audio upload.
+func isRestrictionLikeError(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case 400, 403:
+			return false
+		}
+	}
+	return true
+}
+
+// SummarizerPromptTemplate is the static portion of the prompt sent to Gemini to summarize a
+// video, excluding the per-video title interpolated by summarizerPrompt. It's exported so
+// callers (e.g. the pipeline's cache invalidation) can hash the real prompt text instead of
+// maintaining a separate copy that can drift out of sync with edits here.
+const SummarizerPromptTemplate = `
+	You are an expert in Go programming.
+
+	You will be given a YouTube video URL of a Go programming course recorded with Go version 1.15.
+
+	Your task is to summarize the video by following these guidelines:
+	1. Dissect the video content into distinct chapters based on the topics covered.
+	2. For each chapter, summarize the key concepts and best practices as concise bullet points:
+		- Include relevant Go code snippets.
+		- Do not include video timestamps or references to specific moments in the video.
+	3. Return your response in the following strict **Markdown format only**, with no additional text:
+	# {{title}}
+
+	## Summary
+	(A brief overview of the video content.)
+
+	## Key Points
+	(A list of chapters with their summaries in concise bullet points. Include relevant Go code snippets or examples.)
+	`
+
+func summarizerPrompt(title string) string {
+	return strings.Replace(SummarizerPromptTemplate, "{{title}}", title, 1)
+}
+
+func (p *GeminiProcessor) summarizeFromURI(ctx context.Context, item fetch.VideoItem) (Result, error) {
+	p.logger.Info("Sending request to Gemini for video summarization", "videoId", item.ContentDetails.VideoId, "title", item.Snippet.Title)
+
+	parts := []*genai.Part{
+		genai.NewPartFromURI(fmt.Sprintf("https://www.youtube.com/watch?v=%s", item.ContentDetails.VideoId), "video/mp4"),
+		genai.NewPartFromText(summarizerPrompt(item.Snippet.Title)),
+	}
+
+	return p.generateSummary(ctx, item.ContentDetails.VideoId, parts)
+}
+
+// summarizeFromDownload downloads the audio-only track and auto-generated subtitles via
+// yt-dlp, uploads the audio to Gemini and feeds the transcript alongside it.
+func (p *GeminiProcessor) summarizeFromDownload(ctx context.Context, item fetch.VideoItem) (Result, error) {
+	videoId := item.ContentDetails.VideoId
+	videoDir := filepath.Join(p.cacheDir, videoId)
+	if err := os.MkdirAll(videoDir, 0755); err != nil {
+		return Result{}, fmt.Errorf("Failed to create cache directory %q: %w", videoDir, err)
+	}
+
+	audioPath, err := p.downloadAudioAndSubtitles(ctx, videoId, videoDir)
+	if err != nil {
+		return Result{}, fmt.Errorf("Failed to download video %q with yt-dlp: %w", videoId, err)
+	}
+
+	transcript, err := readTranscript(videoDir)
+	if err != nil {
+		return Result{}, fmt.Errorf("Failed to read auto-generated subtitles for video %q: %w", videoId, err)
+	}
+
+	audioFile, err := p.client.Files.UploadFromPath(ctx, audioPath, &genai.UploadFileConfig{
+		MIMEType: "audio/*",
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("Failed to upload downloaded audio for video %q to Gemini: %w", videoId, err)
+	}
+
+	parts := []*genai.Part{
+		genai.NewPartFromURI(audioFile.URI, audioFile.MIMEType),
+		genai.NewPartFromText(transcript),
+		genai.NewPartFromText(summarizerPrompt(item.Snippet.Title)),
+	}
+
+	return p.generateSummary(ctx, videoId, parts)
+}
+
+// downloadAudioAndSubtitles shells out to yt-dlp for the audio-only track plus
+// auto-generated VTT subtitles, skipping the download if a cached audio file exists.
+func (p *GeminiProcessor) downloadAudioAndSubtitles(ctx context.Context, videoId, videoDir string) (string, error) {
+	audioPath := filepath.Join(videoDir, "audio.m4a")
+	if _, err := os.Stat(audioPath); err == nil {
+		p.logger.Info("Using cached yt-dlp download", "videoId", videoId, "path", audioPath)
+		return audioPath, nil
+	}
+
+	videoUrl := fmt.Sprintf("https://www.youtube.com/watch?v=%s", videoId)
+	output, err := p.executor.Run(ctx, "yt-dlp",
+		"-f", "bestaudio",
+		"--extract-audio", "--audio-format", "m4a",
+		"--write-auto-sub", "--sub-format", "vtt",
+		"-o", filepath.Join(videoDir, "audio.%(ext)s"),
+		videoUrl,
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, output)
+	}
+	return audioPath, nil
+}
+
+// readTranscript concatenates the auto-generated VTT subtitle files in videoDir into a
+// single transcript. The raw file contents are kept as is, VTT headers and cue timings
+// included.
+func readTranscript(videoDir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(videoDir, "*.vtt"))
+	if err != nil {
+		return "", fmt.Errorf("Failed to glob for subtitle files: %w", err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("No auto-generated subtitle files found in %q", videoDir)
+	}
+
+	var transcript strings.Builder
+	for _, match := range matches {
+		raw, err := os.ReadFile(match)
+		if err != nil {
+			return "", fmt.Errorf("Failed to read subtitle file %q: %w", match, err)
+		}
+		transcript.Write(raw)
+		transcript.WriteString("\n")
+	}
+	return transcript.String(), nil
+}
+
+func (p *GeminiProcessor) generateSummary(ctx context.Context, videoId string, parts []*genai.Part) (Result, error) {
+	contents := []*genai.Content{
+		genai.NewContentFromParts(parts, genai.RoleUser),
+	}
+
+	temperature := float32(0.1)
+	thinkingBudget := int32(-1) // Set to -1 for dynamic thinking
+	response, err := withRetry(ctx, func() (*genai.GenerateContentResponse, error) {
+		if err := p.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return p.client.Models.GenerateContent(
+			ctx,
+			p.model,
+			contents,
+			&genai.GenerateContentConfig{
+				Temperature:        &temperature,
+				ResponseModalities: []string{"TEXT"},
+				ThinkingConfig: &genai.ThinkingConfig{
+					ThinkingBudget: &thinkingBudget,
+				},
+			},
+		)
+	})
+	if err != nil {
+		p.logger.Error("Failed to summarize video using Gemini", "videoId", videoId, "error", err)
+		return Result{}, fmt.Errorf("Failed to summarize video using Gemini: %w", err)
+	}
+	p.limiter.RecordUsage(totalTokenCount(response))
+
+	p.logger.Info("Received summarization response from Gemini", "videoId", videoId, "response", len(response.Text()))
+	p.logUsage(videoId, response)
+
+	return Result{Content: response.Text(), TokenCount: candidatesTokenCount(response)}, nil
+}
+
+// ValidatorPromptTemplate is the static portion of the prompt sent to Gemini to validate a
+// summary, excluding the per-video title and summary interpolated by Validate. It's exported
+// so callers (e.g. the pipeline's cache invalidation) can hash the real prompt text instead of
+// maintaining a separate copy that can drift out of sync with edits here.
+const ValidatorPromptTemplate = `
+	You are a technical content editor who is an expert in Go programming.
+
+	You will be given the summary and key points in Markdown format derived from a Go programming course recorded with Go version 1.15.
+
+	Your task is to evaluate each key point present under the "Key Points" section of the Markdown using the provided Go release notes (covering every Go version released after 1.15) by following these guidelines:
+	1. Determine if every key point is **still valid and accurate** in the latest Go version covered by the provided release notes, based on those release notes.
+		- **Only** consider the following sections in the release notes while evaluating the key points: "Changes to the language", "Tools" and "Standard library". Ignore any other sections.
+		- Do **not** evaluate key points expressing opinions, philosophies, or general design principles.
+		- Only focus on factual key points about Go syntax, behavior, deprecation, tooling, etc.
+	2. For any key point that is no longer valid or accurate:
+		- Briefly explain what has changed in the latest Go version that affects the key point.
+		- Cite the **first Go version** where the change was introduced using a numbered format like [1], [2], etc.
+		- Do not cite a Go version unless it is directly relevant to the key point. Also, do not cite multiple versions for the same change (choose the most relevant one).
+		- Provide updated code snippets if the original code is outdated.
+	3. Do not use any prior knowledge about Go. Only base your answers on the provided release notes.
+	4. Return your response in the following strict **Markdown format only**, with no additional text:
+	# {{title}}
+
+	## Summary
+	(Summary passed to you as input, do not change it.)
+
+	## Key Points
+	(Key points passed to you as input, do not change them.)
+
+	## What's New
+	(A list of changes found in the key points based on the release notes, with each change cited to the relevant Go version in [x] numbered format.)
+
+	## Updated Code Snippets
+	(If any code snippets in the key points were outdated, provide the updated versions here. If no updated code snippets are needed, omit this section entirely.)
+
+	## Citations
+	(A list of Go version release notes cited in the format [1], [2], etc. For example:
+	- [1] Go version 1.16
+	- [2] Go version 1.17
+	)
+
+	Here are the summary and key points in Markdown format you need to evaluate:
+	{{summary}}`
+
+func (p *GeminiProcessor) Validate(ctx context.Context, title, summary string) (Result, error) {
+	validatorPrompt := strings.NewReplacer("{{title}}", title, "{{summary}}", summary).Replace(ValidatorPromptTemplate)
+
+	parts := []*genai.Part{}
+	for i, file := range p.releaseNotes {
+		parts = append(parts, genai.NewPartFromText(fmt.Sprintf("[%d] %s", i+1, file.Name)), genai.NewPartFromURI(file.URI, file.MIMEType))
+	}
+	parts = append(parts, genai.NewPartFromText(validatorPrompt))
+
+	contents := []*genai.Content{
+		genai.NewContentFromParts(parts, genai.RoleUser),
+	}
+
+	temperature := float32(0.0)    // Set temperature to 0 for validation
+	thinkingBudget := int32(24576) // Set a high thinking budget for thorough validation
+	response, err := withRetry(ctx, func() (*genai.GenerateContentResponse, error) {
+		if err := p.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return p.client.Models.GenerateContent(
+			ctx,
+			p.model,
+			contents,
+			&genai.GenerateContentConfig{
+				Temperature:        &temperature,
+				ResponseModalities: []string{"TEXT"},
+				ThinkingConfig: &genai.ThinkingConfig{
+					ThinkingBudget: &thinkingBudget,
+				},
+			},
+		)
+	})
+	if err != nil {
+		p.logger.Error("Failed to validate using Gemini", "title", title, "error", err)
+		return Result{}, fmt.Errorf("Failed to validate using Gemini: %w", err)
+	}
+	p.limiter.RecordUsage(totalTokenCount(response))
+
+	p.logger.Info("Received validation response from Gemini", "response", len(response.Text()))
+	p.logUsage("", response)
+
+	return Result{Content: response.Text(), TokenCount: candidatesTokenCount(response)}, nil
+}
+
+func candidatesTokenCount(response *genai.GenerateContentResponse) int32 {
+	if response.UsageMetadata == nil {
+		return 0
+	}
+	return response.UsageMetadata.CandidatesTokenCount
+}
+
+// totalTokenCount returns the full prompt+candidates token count, used to charge the rate
+// limiter's TPM budget. Prompt tokens dominate the summarize call (it uploads an entire
+// video/audio file as input), so limiting on candidates alone would let that call blow through
+// GEMINI_TPM undetected.
+func totalTokenCount(response *genai.GenerateContentResponse) int32 {
+	if response.UsageMetadata == nil {
+		return 0
+	}
+	return response.UsageMetadata.TotalTokenCount
+}
+
+func (p *GeminiProcessor) logUsage(videoId string, response *genai.GenerateContentResponse) {
+	usageMetadata, err := json.MarshalIndent(response.UsageMetadata, "", "  ")
+	if err != nil {
+		p.logger.Error("Failed to marshal usage metadata", "videoId", videoId, "error", err)
+		return
+	}
+
+	var metaMap map[string]any
+	if err := json.Unmarshal(usageMetadata, &metaMap); err != nil {
+		p.logger.Info("Usage metadata is not a valid JSON", "usageMetadata", string(usageMetadata))
+		return
+	}
+	if thoughts, ok := metaMap["thoughtsTokenCount"]; ok {
+		p.logger.Info("Thoughts tokens", "count", thoughts)
+	}
+	if candidates, ok := metaMap["candidatesTokenCount"]; ok {
+		p.logger.Info("Output tokens", "count", candidates)
+	}
+}
@@ -0,0 +1,115 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestDownloadAudioAndSubtitles_UsesCacheWhenPresent(t *testing.T) {
+	videoDir := t.TempDir()
+	audioPath := filepath.Join(videoDir, "audio.m4a")
+	if err := os.WriteFile(audioPath, []byte("cached"), 0644); err != nil {
+		t.Fatalf("Failed to seed cached audio file: %v", err)
+	}
+
+	ran := false
+	p := &GeminiProcessor{
+		logger: discardLogger(),
+		executor: &MockCommandExecutor{
+			RunFunc: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+				ran = true
+				return nil, nil
+			},
+		},
+	}
+
+	got, err := p.downloadAudioAndSubtitles(context.Background(), "abc123", videoDir)
+	if err != nil {
+		t.Fatalf("downloadAudioAndSubtitles returned unexpected error: %v", err)
+	}
+	if got != audioPath {
+		t.Errorf("downloadAudioAndSubtitles = %q, want %q", got, audioPath)
+	}
+	if ran {
+		t.Error("yt-dlp should not run again when the audio file is already cached")
+	}
+}
+
+func TestDownloadAudioAndSubtitles_RunsYtDlpWhenNotCached(t *testing.T) {
+	videoDir := t.TempDir()
+
+	var gotName string
+	p := &GeminiProcessor{
+		executor: &MockCommandExecutor{
+			RunFunc: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+				gotName = name
+				return []byte("ok"), nil
+			},
+		},
+	}
+
+	audioPath, err := p.downloadAudioAndSubtitles(context.Background(), "abc123", videoDir)
+	if err != nil {
+		t.Fatalf("downloadAudioAndSubtitles returned unexpected error: %v", err)
+	}
+	if gotName != "yt-dlp" {
+		t.Errorf("executor invoked command %q, want yt-dlp", gotName)
+	}
+	if audioPath != filepath.Join(videoDir, "audio.m4a") {
+		t.Errorf("downloadAudioAndSubtitles = %q, want audio.m4a under %q", audioPath, videoDir)
+	}
+}
+
+func TestReadTranscript_ConcatenatesSubtitleFiles(t *testing.T) {
+	videoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(videoDir, "video.en.vtt"), []byte("WEBVTT\n\nhello world"), 0644); err != nil {
+		t.Fatalf("Failed to write subtitle fixture: %v", err)
+	}
+
+	transcript, err := readTranscript(videoDir)
+	if err != nil {
+		t.Fatalf("readTranscript returned unexpected error: %v", err)
+	}
+	if transcript == "" {
+		t.Error("readTranscript returned an empty transcript")
+	}
+}
+
+func TestReadTranscript_NoSubtitleFiles(t *testing.T) {
+	if _, err := readTranscript(t.TempDir()); err == nil {
+		t.Fatal("readTranscript should return an error when no subtitle files are present")
+	}
+}
+
+func TestIsRestrictionLikeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"bad API key", genai.APIError{Code: 400}, false},
+		{"quota exhausted", genai.APIError{Code: 403}, false},
+		{"rate limited", genai.APIError{Code: 429}, true},
+		{"server error", genai.APIError{Code: 500}, true},
+		{"unrecognized error", errors.New("ingest failed"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRestrictionLikeError(tt.err); got != tt.want {
+				t.Errorf("isRestrictionLikeError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package process
+
+import (
+	"context"
+
+	"github.com/Ar4v1nd/go-course-modernizer/fetch"
+)
+
+// MockProcessor is a test double for Processor whose behavior is configured via funcs,
+// mirroring the mock command executor pattern used elsewhere for external dependencies.
+type MockProcessor struct {
+	SummarizeFunc func(ctx context.Context, item fetch.VideoItem) (Result, error)
+	ValidateFunc  func(ctx context.Context, title, summary string) (Result, error)
+}
+
+func (m *MockProcessor) Summarize(ctx context.Context, item fetch.VideoItem) (Result, error) {
+	return m.SummarizeFunc(ctx, item)
+}
+
+func (m *MockProcessor) Validate(ctx context.Context, title, summary string) (Result, error) {
+	return m.ValidateFunc(ctx, title, summary)
+}
+
+// MockCommandExecutor is a test double for CommandExecutor whose behavior is configured
+// via a func, so tests can assert on the invoked command without shelling out for real.
+type MockCommandExecutor struct {
+	RunFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+func (m *MockCommandExecutor) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return m.RunFunc(ctx, name, args...)
+}
@@ -0,0 +1,42 @@
+// Package process turns a fetched video into validated, up-to-date Markdown using an LLM
+// backend. The backend is abstracted behind the Processor interface so alternate
+// implementations (OpenAI, Claude, a local Ollama model, ...) can be swapped in.
+package process
+
+import (
+	"context"
+
+	"github.com/Ar4v1nd/go-course-modernizer/fetch"
+)
+
+// Result is the output of a single pipeline stage: the Markdown content it produced and
+// how many tokens the underlying model spent producing it.
+type Result struct {
+	Content    string
+	TokenCount int32
+}
+
+// Processor summarizes a course video and validates its key points against up-to-date
+// reference material. Implementations are free to call out to whichever LLM they like.
+type Processor interface {
+	// Summarize produces the initial Markdown summary for a video.
+	Summarize(ctx context.Context, item fetch.VideoItem) (Result, error)
+	// Validate checks a summary's key points for continued accuracy and returns the
+	// annotated Markdown (summary plus "What's New"/"Citations" sections).
+	Validate(ctx context.Context, title, summary string) (Result, error)
+}
+
+// Run executes the full summarize-then-validate pipeline for a single video.
+func Run(ctx context.Context, proc Processor, item fetch.VideoItem) (summary Result, validated Result, err error) {
+	summary, err = proc.Summarize(ctx, item)
+	if err != nil {
+		return Result{}, Result{}, err
+	}
+
+	validated, err = proc.Validate(ctx, item.Snippet.Title, summary.Content)
+	if err != nil {
+		return summary, Result{}, err
+	}
+
+	return summary, validated, nil
+}
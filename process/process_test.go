@@ -0,0 +1,53 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Ar4v1nd/go-course-modernizer/fetch"
+)
+
+func TestRun_SummarizeAndValidate(t *testing.T) {
+	var item fetch.VideoItem
+	item.Snippet.Title = "Intro to Goroutines"
+
+	mock := &MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (Result, error) {
+			return Result{Content: "## Summary\nsome summary", TokenCount: 100}, nil
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (Result, error) {
+			if summary != "## Summary\nsome summary" {
+				t.Fatalf("Validate got unexpected summary: %q", summary)
+			}
+			return Result{Content: "## Summary\nsome summary\n\n## What's New\nnothing", TokenCount: 200}, nil
+		},
+	}
+
+	summary, validated, err := Run(context.Background(), mock, item)
+	if err != nil {
+		t.Fatalf("Run returned unexpected error: %v", err)
+	}
+	if summary.TokenCount != 100 {
+		t.Errorf("summary.TokenCount = %d, want 100", summary.TokenCount)
+	}
+	if validated.TokenCount != 200 {
+		t.Errorf("validated.TokenCount = %d, want 200", validated.TokenCount)
+	}
+}
+
+func TestRun_SummarizeError(t *testing.T) {
+	mock := &MockProcessor{
+		SummarizeFunc: func(ctx context.Context, item fetch.VideoItem) (Result, error) {
+			return Result{}, errors.New("summarize failed")
+		},
+		ValidateFunc: func(ctx context.Context, title, summary string) (Result, error) {
+			t.Fatal("Validate should not be called when Summarize fails")
+			return Result{}, nil
+		},
+	}
+
+	if _, _, err := Run(context.Background(), mock, fetch.VideoItem{}); err == nil {
+		t.Fatal("Run should return an error when Summarize fails")
+	}
+}
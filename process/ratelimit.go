@@ -0,0 +1,104 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultRPM        = 10
+	defaultTPM        = 250_000
+	defaultMaxRetries = 6
+)
+
+// RateLimiter bounds Gemini calls to a requests-per-minute budget via golang.org/x/time/rate
+// and separately tracks a tokens-per-minute budget from each call's UsageMetadata, since
+// Gemini enforces both independently.
+type RateLimiter struct {
+	requests *rate.Limiter
+
+	mu          sync.Mutex
+	tpm         int
+	windowStart time.Time
+	tokensSpent int
+}
+
+// NewRateLimiter builds a RateLimiter allowing up to rpm requests and tpm tokens per minute.
+func NewRateLimiter(rpm, tpm int) *RateLimiter {
+	return &RateLimiter{
+		requests:    rate.NewLimiter(rate.Limit(float64(rpm)/60.0), rpm),
+		tpm:         tpm,
+		windowStart: time.Time{},
+	}
+}
+
+// RateLimiterFromEnv builds a RateLimiter from GEMINI_RPM/GEMINI_TPM, falling back to
+// sensible defaults when unset or invalid.
+func RateLimiterFromEnv() *RateLimiter {
+	return NewRateLimiter(envInt("GEMINI_RPM", defaultRPM), envInt("GEMINI_TPM", defaultTPM))
+}
+
+func envInt(name string, fallback int) int {
+	raw, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// Wait blocks until both the request and token budgets have room for one more call.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if err := l.requests.Wait(ctx); err != nil {
+		return fmt.Errorf("Failed waiting for request rate limit: %w", err)
+	}
+	return l.waitForTokenBudget(ctx)
+}
+
+func (l *RateLimiter) waitForTokenBudget(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		if now.Sub(l.windowStart) >= time.Minute {
+			l.windowStart = now
+			l.tokensSpent = 0
+		}
+		if l.tokensSpent < l.tpm {
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Minute - now.Sub(l.windowStart)
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// RecordUsage accounts tokens spent by a completed call against the tokens-per-minute budget.
+func (l *RateLimiter) RecordUsage(tokens int32) {
+	if tokens <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Since(l.windowStart) >= time.Minute {
+		l.windowStart = time.Now()
+		l.tokensSpent = 0
+	}
+	l.tokensSpent += int(tokens)
+}
@@ -0,0 +1,33 @@
+package process
+
+import "testing"
+
+func TestRateLimiter_RecordUsageAccumulatesWithinWindow(t *testing.T) {
+	l := NewRateLimiter(60, 1000)
+
+	l.RecordUsage(400)
+	l.RecordUsage(400)
+
+	l.mu.Lock()
+	spent := l.tokensSpent
+	l.mu.Unlock()
+
+	if spent != 800 {
+		t.Errorf("tokensSpent = %d, want 800", spent)
+	}
+}
+
+func TestRateLimiter_RecordUsageIgnoresNonPositive(t *testing.T) {
+	l := NewRateLimiter(60, 1000)
+
+	l.RecordUsage(0)
+	l.RecordUsage(-5)
+
+	l.mu.Lock()
+	spent := l.tokensSpent
+	l.mu.Unlock()
+
+	if spent != 0 {
+		t.Errorf("tokensSpent = %d, want 0", spent)
+	}
+}
@@ -0,0 +1,112 @@
+package process
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+const (
+	retryBaseDelay = 2 * time.Second
+	retryMaxDelay  = 5 * time.Minute
+)
+
+var retryAfterPattern = regexp.MustCompile(`(?i)retry[- ]after[^0-9]*(\d+)`)
+
+// retryMaxAttemptsFromEnv reads GEMINI_MAX_RETRIES, falling back to defaultMaxRetries.
+func retryMaxAttemptsFromEnv() int {
+	return envInt("GEMINI_MAX_RETRIES", defaultMaxRetries)
+}
+
+// withRetry calls fn, retrying with full-jitter exponential backoff on retryable errors
+// (429, 500, 503, DEADLINE_EXCEEDED) up to GEMINI_MAX_RETRIES attempts. Terminal errors
+// (400, 403, and anything else not recognized as retryable) are returned immediately.
+func withRetry[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	maxAttempts := retryMaxAttemptsFromEnv()
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		result, err := fn()
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if !isRetryable(err) {
+			return result, err
+		}
+
+		serverDelay, hasServerDelay := retryAfter(err)
+		delay := backoffDelay(attempt, serverDelay, hasServerDelay)
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	var zero T
+	return zero, lastErr
+}
+
+// isRetryable reports whether err represents a transient failure worth retrying.
+func isRetryable(err error) bool {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.Code {
+		case 429, 500, 503:
+			return true
+		case 400, 403:
+			return false
+		}
+	}
+
+	msg := strings.ToUpper(err.Error())
+	return strings.Contains(msg, "429") ||
+		strings.Contains(msg, "500") ||
+		strings.Contains(msg, "503") ||
+		strings.Contains(msg, "DEADLINE_EXCEEDED") ||
+		strings.Contains(msg, "UNAVAILABLE")
+}
+
+// retryAfter extracts a server-provided Retry-After duration (in seconds) from err, if present.
+func retryAfter(err error) (time.Duration, bool) {
+	match := retryAfterPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+	seconds, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// backoffDelay computes a full-jitter exponential backoff delay for the given (0-indexed)
+// attempt, honoring a server-provided Retry-After when present.
+func backoffDelay(attempt int, serverDelay time.Duration, hasServerDelay bool) time.Duration {
+	if hasServerDelay {
+		return serverDelay
+	}
+
+	maxDelay := float64(retryMaxDelay)
+	base := float64(retryBaseDelay) * math.Pow(2, float64(attempt))
+	if base > maxDelay {
+		base = maxDelay
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(base)+1))
+	if err != nil {
+		return time.Duration(base)
+	}
+	return time.Duration(n.Int64())
+}
@@ -0,0 +1,61 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRetry_SucceedsAfterRetryableErrors(t *testing.T) {
+	t.Setenv("GEMINI_MAX_RETRIES", "5")
+
+	attempts := 0
+	result, err := withRetry(context.Background(), func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("429 Too Many Requests")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry returned unexpected error: %v", err)
+	}
+	if result != "ok" {
+		t.Errorf("result = %q, want ok", result)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestWithRetry_StopsOnTerminalError(t *testing.T) {
+	attempts := 0
+	_, err := withRetry(context.Background(), func() (string, error) {
+		attempts++
+		return "", errors.New("400 Bad Request")
+	})
+	if err == nil {
+		t.Fatal("withRetry should return an error for a terminal failure")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 (no retries for a terminal error)", attempts)
+	}
+}
+
+func TestRetryAfter_ParsesServerHint(t *testing.T) {
+	delay, ok := retryAfter(errors.New("rate limited, retry after 30 seconds"))
+	if !ok {
+		t.Fatal("retryAfter should find a Retry-After hint")
+	}
+	if delay != 30*time.Second {
+		t.Errorf("delay = %v, want 30s", delay)
+	}
+}
+
+func TestBackoffDelay_CapsAtMax(t *testing.T) {
+	delay := backoffDelay(20, 0, false)
+	if delay > retryMaxDelay {
+		t.Errorf("backoffDelay = %v, want <= %v", delay, retryMaxDelay)
+	}
+}
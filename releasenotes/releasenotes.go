@@ -0,0 +1,201 @@
+// Package releasenotes discovers and caches Go release notes from go.dev so the validator
+// no longer needs a manually curated local PDF directory.
+package releasenotes
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DefaultFloor is the lowest Go minor version whose release notes are fetched by default.
+const DefaultFloor = 16
+
+// releaseIndexURL and releaseNoteURLFormat are vars rather than consts so tests can point
+// them at an httptest.Server instead of go.dev.
+var (
+	releaseIndexURL      = "https://go.dev/doc/devel/release"
+	releaseNoteURLFormat = "https://go.dev/doc/go%s"
+)
+
+// sections lists the release-note headings the validator prompt actually cares about;
+// everything else (Ports, Performance, etc.) is dropped to keep the cached notes small.
+var sections = []string{"Changes to the language", "Tools", "Standard library"}
+
+var versionLinkPattern = regexp.MustCompile(`^go1\.(\d+)(?:\.[\d]+)?$`)
+
+// Note is a single Go version's cached, trimmed release notes.
+type Note struct {
+	Version string // e.g. "1.22"
+	Path    string // cached Markdown file path
+	Hash    string // sha256 of the cached content, for upload deduping
+}
+
+// Discover enumerates every minor Go version >= floor listed on the release notes index.
+func Discover(ctx context.Context, floor int) ([]string, error) {
+	doc, err := fetchDocument(ctx, releaseIndexURL, "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch Go release index: %w", err)
+	}
+
+	seen := make(map[int]bool)
+	var minors []int
+	doc.Find("a[href], h2[id]").Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("id")
+		if id == "" {
+			if href, ok := s.Attr("href"); ok {
+				id = strings.TrimPrefix(href, "#")
+			}
+		}
+		match := versionLinkPattern.FindStringSubmatch(id)
+		if match == nil {
+			return
+		}
+		minor, err := strconv.Atoi(match[1])
+		if err != nil || minor < floor || seen[minor] {
+			return
+		}
+		seen[minor] = true
+		minors = append(minors, minor)
+	})
+
+	if len(minors) == 0 {
+		return nil, fmt.Errorf("No Go versions >= 1.%d found on %s", floor, releaseIndexURL)
+	}
+
+	sort.Ints(minors)
+	versions := make([]string, len(minors))
+	for i, minor := range minors {
+		versions[i] = fmt.Sprintf("1.%d", minor)
+	}
+	return versions, nil
+}
+
+// FetchAndCache fetches the HTML release notes for version, extracts only the sections
+// relevant to the validator prompt, and caches them under cacheDir/go1.NN.md. A cached
+// copy is reused, via a conditional request against the stored ETag, when unchanged.
+func FetchAndCache(ctx context.Context, version, cacheDir string) (Note, error) {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return Note{}, fmt.Errorf("Failed to create release notes cache directory %q: %w", cacheDir, err)
+	}
+
+	cachePath := filepath.Join(cacheDir, fmt.Sprintf("go%s.md", version))
+	etagPath := cachePath + ".etag"
+
+	etag := ""
+	if raw, err := os.ReadFile(etagPath); err == nil {
+		etag = strings.TrimSpace(string(raw))
+	}
+
+	url := fmt.Sprintf(releaseNoteURLFormat, version)
+	doc, newEtag, notModified, err := fetchDocumentConditional(ctx, url, etag)
+	if err != nil {
+		return Note{}, fmt.Errorf("Failed to fetch release notes for Go %s: %w", version, err)
+	}
+
+	if notModified {
+		content, err := os.ReadFile(cachePath)
+		if err != nil {
+			return Note{}, fmt.Errorf("Failed to read cached release notes %q: %w", cachePath, err)
+		}
+		return Note{Version: version, Path: cachePath, Hash: contentHash(content)}, nil
+	}
+
+	markdown := extractSections(doc)
+	if err := os.WriteFile(cachePath, []byte(markdown), 0644); err != nil {
+		return Note{}, fmt.Errorf("Failed to write cached release notes %q: %w", cachePath, err)
+	}
+	if newEtag != "" {
+		if err := os.WriteFile(etagPath, []byte(newEtag), 0644); err != nil {
+			return Note{}, fmt.Errorf("Failed to write ETag for %q: %w", cachePath, err)
+		}
+	}
+
+	return Note{Version: version, Path: cachePath, Hash: contentHash([]byte(markdown))}, nil
+}
+
+// extractSections pulls the "Changes to the language", "Tools" and "Standard library"
+// sections out of a release notes page and renders them as trimmed Markdown.
+func extractSections(doc *goquery.Document) string {
+	var markdown strings.Builder
+
+	doc.Find("h2, h3").Each(func(_ int, heading *goquery.Selection) {
+		title := strings.TrimSpace(heading.Text())
+		if !matchesSection(title) {
+			return
+		}
+
+		markdown.WriteString("## " + title + "\n\n")
+		for node := heading.Next(); node.Length() > 0 && !isHeading(node); node = node.Next() {
+			text := strings.TrimSpace(node.Text())
+			if text != "" {
+				markdown.WriteString(text + "\n\n")
+			}
+		}
+	})
+
+	return markdown.String()
+}
+
+func matchesSection(title string) bool {
+	for _, section := range sections {
+		if strings.EqualFold(title, section) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHeading(s *goquery.Selection) bool {
+	tag := goquery.NodeName(s)
+	return tag == "h1" || tag == "h2" || tag == "h3"
+}
+
+func fetchDocument(ctx context.Context, url, etag string) (*goquery.Document, error) {
+	doc, _, _, err := fetchDocumentConditional(ctx, url, etag)
+	return doc, err
+}
+
+func fetchDocumentConditional(ctx context.Context, url, etag string) (doc *goquery.Document, newEtag string, notModified bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("Failed to create request: %w", err)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("Failed to make request to %q: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("Received non-200 response from %q: %s", url, resp.Status)
+	}
+
+	doc, err = goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("Failed to parse HTML from %q: %w", url, err)
+	}
+	return doc, resp.Header.Get("ETag"), false, nil
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,103 @@
+package releasenotes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const fixtureHTML = `
+<html><body>
+<h2 id="introduction">Introduction</h2>
+<p>Go 1.22 is a major release.</p>
+<h2 id="language">Changes to the language</h2>
+<p>Range over int is now allowed.</p>
+<h2 id="tools">Tools</h2>
+<p>go vet now checks range-over-func.</p>
+<h2 id="ports">Ports</h2>
+<p>Irrelevant platform notes.</p>
+</body></html>
+`
+
+func TestExtractSections_KeepsOnlyRelevantHeadings(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fixtureHTML))
+	if err != nil {
+		t.Fatalf("Failed to parse fixture HTML: %v", err)
+	}
+
+	markdown := extractSections(doc)
+
+	if !strings.Contains(markdown, "Changes to the language") {
+		t.Error("extractSections dropped the \"Changes to the language\" section")
+	}
+	if !strings.Contains(markdown, "Tools") {
+		t.Error("extractSections dropped the \"Tools\" section")
+	}
+	if strings.Contains(markdown, "Introduction") || strings.Contains(markdown, "Ports") {
+		t.Error("extractSections kept an irrelevant section")
+	}
+}
+
+func TestMatchesSection(t *testing.T) {
+	if !matchesSection("standard library") {
+		t.Error("matchesSection should be case-insensitive")
+	}
+	if matchesSection("Performance") {
+		t.Error("matchesSection should reject sections outside the validator's scope")
+	}
+}
+
+func TestFetchAndCache_ReusesCacheOn304(t *testing.T) {
+	const etag = `"etag-1"`
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, `<html><body><h2 id="language">Changes to the language</h2><p>Something changed.</p></body></html>`)
+	}))
+	defer server.Close()
+
+	original := releaseNoteURLFormat
+	releaseNoteURLFormat = server.URL + "/go%s"
+	defer func() { releaseNoteURLFormat = original }()
+
+	cacheDir := t.TempDir()
+
+	first, err := FetchAndCache(context.Background(), "1.99", cacheDir)
+	if err != nil {
+		t.Fatalf("FetchAndCache returned unexpected error on first fetch: %v", err)
+	}
+	content, err := os.ReadFile(first.Path)
+	if err != nil {
+		t.Fatalf("Failed to read cached notes: %v", err)
+	}
+	if !strings.Contains(string(content), "Changes to the language") {
+		t.Errorf("cached notes missing expected section: %q", content)
+	}
+
+	second, err := FetchAndCache(context.Background(), "1.99", cacheDir)
+	if err != nil {
+		t.Fatalf("FetchAndCache returned unexpected error on second fetch: %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("server received %d requests, want 2 (one per fetch)", requests)
+	}
+	if second.Hash != first.Hash {
+		t.Errorf("FetchAndCache hash changed across a 304 response: %q != %q", second.Hash, first.Hash)
+	}
+	if second.Path != filepath.Join(cacheDir, "go1.99.md") {
+		t.Errorf("FetchAndCache cache path = %q, want go1.99.md under %q", second.Path, cacheDir)
+	}
+}
@@ -0,0 +1,86 @@
+package releasenotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"google.golang.org/genai"
+)
+
+// Uploader uploads a file to Gemini, abstracted so UploadAll can be tested without hitting
+// the real API. *genai.Files satisfies this directly.
+type Uploader interface {
+	UploadFromPath(ctx context.Context, path string, config *genai.UploadFileConfig) (*genai.File, error)
+}
+
+// manifest maps a cached note's Version to the content Hash that was last uploaded to
+// Gemini for it. It does not prevent uploads: UploadAll re-uploads every note on every run
+// and only logs when a note's hash is unchanged.
+type manifest map[string]string
+
+func loadManifest(path string) (manifest, error) {
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read upload manifest %q: %w", path, err)
+	}
+
+	m := manifest{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("Failed to parse upload manifest %q: %w", path, err)
+	}
+	return m, nil
+}
+
+func (m manifest) save(path string) error {
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal upload manifest: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("Failed to write upload manifest %q: %w", path, err)
+	}
+	return nil
+}
+
+// UploadAll uploads every note to Gemini and returns the resulting file handles.
+//
+// Gemini file uploads don't persist across runs (handles expire independently of this
+// process), so every note must actually be (re-)uploaded every run regardless of whether
+// its content changed - a version missing from the returned slice would silently drop out
+// of the validator's citation corpus. manifestPath only records each version's last
+// uploaded content hash, so callers/logs can tell which notes actually changed.
+func UploadAll(ctx context.Context, uploader Uploader, logger *slog.Logger, notes []Note, manifestPath string) ([]*genai.File, error) {
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*genai.File, 0, len(notes))
+	for _, note := range notes {
+		if m[note.Version] == note.Hash {
+			logger.Info("Release notes unchanged since last upload, re-uploading since Gemini file handles don't persist across runs", "version", note.Version)
+		}
+
+		f, err := uploader.UploadFromPath(ctx, note.Path, &genai.UploadFileConfig{
+			MIMEType: "text/markdown",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to upload release notes for Go %s to Gemini: %w", note.Version, err)
+		}
+		logger.Info("Successfully uploaded release notes", "version", note.Version, "file", note.Path)
+
+		files = append(files, f)
+		m[note.Version] = note.Hash
+	}
+
+	if err := m.save(manifestPath); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
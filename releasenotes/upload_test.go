@@ -0,0 +1,100 @@
+package releasenotes
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/genai"
+)
+
+// mockUploader is a test double for Uploader whose behavior is configured via a func,
+// mirroring the mock command executor pattern used elsewhere for external dependencies.
+type mockUploader struct {
+	UploadFromPathFunc func(ctx context.Context, path string, config *genai.UploadFileConfig) (*genai.File, error)
+}
+
+func (m *mockUploader) UploadFromPath(ctx context.Context, path string, config *genai.UploadFileConfig) (*genai.File, error) {
+	return m.UploadFromPathFunc(ctx, path, config)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestUploadAll_ReUploadsOnHashMatch(t *testing.T) {
+	dir := t.TempDir()
+	notePath := filepath.Join(dir, "go1.22.md")
+	if err := os.WriteFile(notePath, []byte("## Tools\nsome change"), 0644); err != nil {
+		t.Fatalf("Failed to write note fixture: %v", err)
+	}
+	notes := []Note{{Version: "1.22", Path: notePath, Hash: "same-hash"}}
+
+	manifestPath := filepath.Join(dir, "uploaded.json")
+	if err := (manifest{"1.22": "same-hash"}).save(manifestPath); err != nil {
+		t.Fatalf("Failed to seed manifest: %v", err)
+	}
+
+	var uploadCount int
+	uploader := &mockUploader{
+		UploadFromPathFunc: func(ctx context.Context, path string, config *genai.UploadFileConfig) (*genai.File, error) {
+			uploadCount++
+			return &genai.File{Name: "files/fake", URI: "https://example.com/files/fake", MIMEType: config.MIMEType}, nil
+		},
+	}
+
+	files, err := UploadAll(context.Background(), uploader, discardLogger(), notes, manifestPath)
+	if err != nil {
+		t.Fatalf("UploadAll returned unexpected error: %v", err)
+	}
+	if uploadCount != 1 {
+		t.Fatalf("uploader was called %d times, want 1 (unchanged notes must still be re-uploaded)", uploadCount)
+	}
+	if len(files) != 1 {
+		t.Fatalf("UploadAll returned %d files, want 1 - an unchanged note must not be dropped from the corpus", len(files))
+	}
+
+	raw, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var saved manifest
+	if err := json.Unmarshal(raw, &saved); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	if saved["1.22"] != "same-hash" {
+		t.Errorf(`manifest["1.22"] = %q, want "same-hash"`, saved["1.22"])
+	}
+}
+
+func TestUploadAll_WritesManifestForNewVersion(t *testing.T) {
+	dir := t.TempDir()
+	notePath := filepath.Join(dir, "go1.23.md")
+	if err := os.WriteFile(notePath, []byte("## Tools\nanother change"), 0644); err != nil {
+		t.Fatalf("Failed to write note fixture: %v", err)
+	}
+	notes := []Note{{Version: "1.23", Path: notePath, Hash: "new-hash"}}
+	manifestPath := filepath.Join(dir, "uploaded.json")
+
+	uploader := &mockUploader{
+		UploadFromPathFunc: func(ctx context.Context, path string, config *genai.UploadFileConfig) (*genai.File, error) {
+			return &genai.File{Name: "files/fake", URI: "https://example.com/files/fake", MIMEType: config.MIMEType}, nil
+		},
+	}
+
+	if _, err := UploadAll(context.Background(), uploader, discardLogger(), notes, manifestPath); err != nil {
+		t.Fatalf("UploadAll returned unexpected error: %v", err)
+	}
+
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		t.Fatalf("Failed to load manifest: %v", err)
+	}
+	if m["1.23"] != "new-hash" {
+		t.Errorf(`manifest["1.23"] = %q, want "new-hash"`, m["1.23"])
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WriteIndex writes dir/index.json and dir/index.md, aggregating every video result
+// sorted by playlist position so consumers can build a static site or search index from
+// a single file.
+func WriteIndex(dir string, results []VideoResult) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Error creating markdown directory: %w", err)
+	}
+
+	sorted := make([]VideoResult, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].PlaylistPosition < sorted[j].PlaylistPosition
+	})
+
+	if err := writeIndexJSON(dir, sorted); err != nil {
+		return err
+	}
+	return writeIndexMarkdown(dir, sorted)
+}
+
+func writeIndexJSON(dir string, results []VideoResult) error {
+	raw, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal index: %w", err)
+	}
+
+	path := filepath.Join(dir, "index.json")
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("Error writing index JSON %q: %w", path, err)
+	}
+	return nil
+}
+
+func writeIndexMarkdown(dir string, results []VideoResult) error {
+	var markdown strings.Builder
+	markdown.WriteString("# Go Course Videos\n\n")
+	for _, result := range results {
+		markdown.WriteString(fmt.Sprintf(
+			"%d. [%s](%s.md) ([data](%s.json))\n",
+			result.PlaylistPosition, result.Title, SafeFilename(result.Title), result.VideoId,
+		))
+	}
+
+	path := filepath.Join(dir, "index.md")
+	if err := os.WriteFile(path, []byte(markdown.String()), 0644); err != nil {
+		return fmt.Errorf("Error writing index Markdown %q: %w", path, err)
+	}
+	return nil
+}
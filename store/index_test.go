@@ -0,0 +1,37 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteIndex_SortsByPlaylistPosition(t *testing.T) {
+	dir := t.TempDir()
+	results := []VideoResult{
+		{VideoId: "b", Title: "Second", PlaylistPosition: 1},
+		{VideoId: "a", Title: "First", PlaylistPosition: 0},
+	}
+
+	if err := WriteIndex(dir, results); err != nil {
+		t.Fatalf("WriteIndex returned unexpected error: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		t.Fatalf("Failed to read index.json: %v", err)
+	}
+
+	var got []VideoResult
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("Failed to parse index.json: %v", err)
+	}
+	if len(got) != 2 || got[0].VideoId != "a" || got[1].VideoId != "b" {
+		t.Fatalf("index.json not sorted by playlist position: %+v", got)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "index.md")); err != nil {
+		t.Errorf("index.md was not written: %v", err)
+	}
+}
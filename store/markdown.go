@@ -0,0 +1,28 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var titleSlashes = regexp.MustCompile(`/`)
+
+// SafeFilename replaces slashes in title so it can't be mistaken for a nested path.
+func SafeFilename(title string) string {
+	return titleSlashes.ReplaceAllString(title, "_")
+}
+
+// WriteMarkdown writes a video's validated Markdown content under dir/<title>.md.
+func WriteMarkdown(dir, title, content string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Error creating markdown directory: %w", err)
+	}
+
+	filePath := filepath.Join(dir, fmt.Sprintf("%s.md", SafeFilename(title)))
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("Error writing summary to file %q: %w", filePath, err)
+	}
+	return nil
+}
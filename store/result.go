@@ -0,0 +1,178 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Citation is one release-note citation referenced from a video's "What's New" section.
+type Citation struct {
+	Number  int    `json:"number"`
+	Version string `json:"version"`
+}
+
+// KeyPointStatus records whether a single key point is still valid in the latest Go
+// version, and if not, which version introduced the change.
+type KeyPointStatus struct {
+	KeyPoint         string `json:"keyPoint"`
+	StillValid       bool   `json:"stillValid"`
+	ChangedInVersion string `json:"changedInVersion,omitempty"`
+}
+
+// VideoResult is the machine-readable counterpart to a video's Markdown output.
+type VideoResult struct {
+	VideoId          string           `json:"videoId"`
+	Title            string           `json:"title"`
+	PublishedAt      string           `json:"publishedAt"`
+	PlaylistPosition int              `json:"playlistPosition"`
+	Model            string           `json:"model"`
+	PromptHash       string           `json:"promptHash"`
+	SummarizerTokens int32            `json:"summarizerTokens"`
+	ValidatorTokens  int32            `json:"validatorTokens"`
+	Citations        []Citation       `json:"citations,omitempty"`
+	KeyPoints        []KeyPointStatus `json:"keyPoints,omitempty"`
+	MarkdownPath     string           `json:"markdownPath"`
+}
+
+var (
+	citationPattern = regexp.MustCompile(`(?m)^\s*-\s*\[(\d+)\]\s*(.+?)\s*$`)
+	bulletPattern   = regexp.MustCompile(`(?m)^\s*-\s*(.+?)\s*$`)
+	citationRefRe   = regexp.MustCompile(`\[(\d+)\]`)
+)
+
+// ParseVideoResult extracts citations and key-point validity from a video's validated
+// Markdown content to build its JSON sidecar.
+func ParseVideoResult(content string, videoId, title, publishedAt string, position int, model, promptHash string, summarizerTokens, validatorTokens int32, markdownPath string) VideoResult {
+	citations := parseCitations(extractSection(content, "Citations"))
+
+	result := VideoResult{
+		VideoId:          videoId,
+		Title:            title,
+		PublishedAt:      publishedAt,
+		PlaylistPosition: position,
+		Model:            model,
+		PromptHash:       promptHash,
+		SummarizerTokens: summarizerTokens,
+		ValidatorTokens:  validatorTokens,
+		Citations:        citations,
+		KeyPoints:        parseKeyPoints(extractSection(content, "Key Points"), extractSection(content, "What's New"), citations),
+		MarkdownPath:     markdownPath,
+	}
+	return result
+}
+
+// extractSection returns the body text of a "## <heading>" section, up to the next "## "
+// heading or the end of the document.
+func extractSection(content, heading string) string {
+	headingRe := regexp.MustCompile(`(?im)^##\s*` + regexp.QuoteMeta(heading) + `\s*$`)
+	loc := headingRe.FindStringIndex(content)
+	if loc == nil {
+		return ""
+	}
+
+	rest := content[loc[1]:]
+	if next := regexp.MustCompile(`(?m)^##\s`).FindStringIndex(rest); next != nil {
+		rest = rest[:next[0]]
+	}
+	return strings.TrimSpace(rest)
+}
+
+func parseCitations(section string) []Citation {
+	if section == "" {
+		return nil
+	}
+
+	var citations []Citation
+	for _, match := range citationPattern.FindAllStringSubmatch(section, -1) {
+		number := 0
+		fmt.Sscanf(match[1], "%d", &number)
+		citations = append(citations, Citation{Number: number, Version: match[2]})
+	}
+	return citations
+}
+
+// keyPointPhraseLength is the number of consecutive words from a "Key Points" bullet that
+// must reappear in a "What's New" bullet for the two to be considered about the same key
+// point. The validator is instructed to explain changes in its own words, but in practice it
+// keeps quoting a run of the original wording, which this relies on.
+const keyPointPhraseLength = 4
+
+// parseKeyPoints builds a per-key-point validity list by cross-referencing every bullet under
+// "## Key Points" against the changes listed under "## What's New": a key point referenced by
+// a "What's New" entry is marked invalid with the version that introduced the change, and
+// every other key point is still considered valid.
+func parseKeyPoints(keyPointsSection, whatsNewSection string, citations []Citation) []KeyPointStatus {
+	if keyPointsSection == "" {
+		return nil
+	}
+
+	versionByNumber := make(map[int]string, len(citations))
+	for _, c := range citations {
+		versionByNumber[c.Number] = c.Version
+	}
+	changes := bulletPattern.FindAllString(whatsNewSection, -1)
+
+	var keyPoints []KeyPointStatus
+	for _, match := range bulletPattern.FindAllStringSubmatch(keyPointsSection, -1) {
+		bullet := match[1]
+		status := KeyPointStatus{KeyPoint: bullet, StillValid: true}
+
+		if change := matchingChange(bullet, changes); change != "" {
+			status.StillValid = false
+			if ref := citationRefRe.FindStringSubmatch(change); ref != nil {
+				number := 0
+				fmt.Sscanf(ref[1], "%d", &number)
+				status.ChangedInVersion = versionByNumber[number]
+			}
+		}
+		keyPoints = append(keyPoints, status)
+	}
+	return keyPoints
+}
+
+// matchingChange returns the "What's New" bullet that discusses keyPoint, identified by a
+// shared run of keyPointPhraseLength consecutive words, or "" if none of the changes mention
+// it.
+func matchingChange(keyPoint string, changes []string) string {
+	words := strings.Fields(strings.ToLower(keyPoint))
+	if len(words) == 0 {
+		return ""
+	}
+
+	phraseLen := keyPointPhraseLength
+	if len(words) < phraseLen {
+		phraseLen = len(words)
+	}
+
+	for _, change := range changes {
+		lowerChange := strings.ToLower(change)
+		for i := 0; i+phraseLen <= len(words); i++ {
+			if strings.Contains(lowerChange, strings.Join(words[i:i+phraseLen], " ")) {
+				return change
+			}
+		}
+	}
+	return ""
+}
+
+// WriteVideoResultJSON writes result as dir/<videoId>.json.
+func WriteVideoResultJSON(dir string, result VideoResult) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Error creating markdown directory: %w", err)
+	}
+
+	raw, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal video result for %q: %w", result.VideoId, err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s.json", result.VideoId))
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return fmt.Errorf("Error writing video result JSON %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import "testing"
+
+const fixtureMarkdown = `# Goroutines and Channels
+
+## Summary
+An overview of goroutines.
+
+## Key Points
+- Goroutines are lightweight threads managed by the Go runtime.
+- Use the testing package's T.Run to organize related subtests.
+
+## What's New
+- The claim that goroutines are lightweight threads managed by the Go runtime no longer holds after the scheduler rewrite in Go 1.20 [1]
+
+## Citations
+- [1] Go version 1.20
+`
+
+func TestParseVideoResult(t *testing.T) {
+	result := ParseVideoResult(fixtureMarkdown, "abc123", "Goroutines and Channels", "2021-01-01T00:00:00Z", 3, "gemini-2.5-flash", "hash123", 100, 200, "markdown/Goroutines and Channels.md")
+
+	if len(result.Citations) != 1 || result.Citations[0].Version != "Go version 1.20" {
+		t.Fatalf("unexpected citations: %+v", result.Citations)
+	}
+	if len(result.KeyPoints) != 2 {
+		t.Fatalf("expected 2 key point entries, got %d", len(result.KeyPoints))
+	}
+
+	changed := result.KeyPoints[0]
+	if changed.StillValid {
+		t.Error("a key point referenced under What's New should not be marked still valid")
+	}
+	if changed.ChangedInVersion != "Go version 1.20" {
+		t.Errorf("ChangedInVersion = %q, want %q", changed.ChangedInVersion, "Go version 1.20")
+	}
+
+	unchanged := result.KeyPoints[1]
+	if !unchanged.StillValid {
+		t.Error("a key point not mentioned under What's New should still be marked valid")
+	}
+	if unchanged.ChangedInVersion != "" {
+		t.Errorf("ChangedInVersion = %q, want empty", unchanged.ChangedInVersion)
+	}
+}
+
+func TestParseVideoResult_NoChanges(t *testing.T) {
+	markdown := "# Title\n\n## Summary\nsummary\n\n## Key Points\n- some point\n"
+	result := ParseVideoResult(markdown, "abc123", "Title", "2021-01-01T00:00:00Z", 1, "gemini-2.5-flash", "hash123", 100, 200, "markdown/Title.md")
+
+	if len(result.Citations) != 0 {
+		t.Errorf("expected no citations, got %+v", result.Citations)
+	}
+	if len(result.KeyPoints) != 1 {
+		t.Fatalf("expected 1 key point entry, got %d", len(result.KeyPoints))
+	}
+	if !result.KeyPoints[0].StillValid {
+		t.Error("a key point with no What's New section should be marked still valid")
+	}
+}
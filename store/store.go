@@ -0,0 +1,134 @@
+// Package store persists pipeline state across runs and writes the Markdown output.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Stage identifies a step of the per-video pipeline.
+type Stage string
+
+const (
+	StageFetched    Stage = "fetched"
+	StageSummarized Stage = "summarized"
+	StageValidated  Stage = "validated"
+	StageWritten    Stage = "written"
+)
+
+// StageRecord captures when a pipeline stage completed and how many tokens it consumed.
+type StageRecord struct {
+	CompletedAt time.Time `json:"completedAt"`
+	TokenCount  int32     `json:"tokenCount,omitempty"`
+}
+
+// VideoState is the persisted, per-video snapshot of pipeline progress. A rerun reuses
+// the Validated blob instead of calling the Processor again as long as PublishedAt,
+// PromptHash and ModelVersion are unchanged from the last successful run. The Summary
+// blob is recorded but not reused: a video without a validated result is summarized again.
+type VideoState struct {
+	VideoId      string                `json:"videoId"`
+	PublishedAt  string                `json:"publishedAt"`
+	PromptHash   string                `json:"promptHash"`
+	ModelVersion string                `json:"modelVersion"`
+	Stages       map[Stage]StageRecord `json:"stages"`
+	Summary      string                `json:"summary,omitempty"`
+	Validated    string                `json:"validated,omitempty"`
+}
+
+// Store is a JSON-file-backed state store keyed by videoId. It is safe for concurrent use.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data map[string]*VideoState
+}
+
+// New loads the state store from path, creating an empty one if the file doesn't exist yet.
+func New(path string) (*Store, error) {
+	s := &Store{path: path, data: make(map[string]*VideoState)}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read state store %q: %w", path, err)
+	}
+	if len(raw) == 0 {
+		return s, nil
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("Failed to parse state store %q: %w", path, err)
+	}
+	return s, nil
+}
+
+// Get returns a copy of the recorded state for videoId, if any.
+func (s *Store) Get(videoId string) (VideoState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.data[videoId]
+	if !ok {
+		return VideoState{}, false
+	}
+	copied := *state
+	copied.Stages = make(map[Stage]StageRecord, len(state.Stages))
+	for stage, record := range state.Stages {
+		copied.Stages[stage] = record
+	}
+	return copied, true
+}
+
+// Update mutates (creating if absent) the state for videoId and persists the store to disk.
+func (s *Store) Update(videoId string, fn func(*VideoState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.data[videoId]
+	if !ok {
+		state = &VideoState{VideoId: videoId, Stages: make(map[Stage]StageRecord)}
+		s.data[videoId] = state
+	}
+	if state.Stages == nil {
+		state.Stages = make(map[Stage]StageRecord)
+	}
+	fn(state)
+
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal state store: %w", err)
+	}
+	if err := os.WriteFile(s.path, raw, 0644); err != nil {
+		return fmt.Errorf("Failed to write state store %q: %w", s.path, err)
+	}
+	return nil
+}
+
+// All returns every recorded video state, for use by the `status` subcommand.
+func (s *Store) All() map[string]*VideoState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all := make(map[string]*VideoState, len(s.data))
+	for k, v := range s.data {
+		copied := *v
+		all[k] = &copied
+	}
+	return all
+}
+
+// PromptHash returns a short, stable fingerprint of the prompts used for a video so that
+// editing a prompt invalidates any cached summarize/validate output for that video.
+func PromptHash(prompts ...string) string {
+	h := sha256.New()
+	for _, p := range prompts {
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
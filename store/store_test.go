@@ -0,0 +1,118 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNew_MissingFileStartsEmpty(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+	if len(s.All()) != 0 {
+		t.Fatalf("expected empty store, got %+v", s.All())
+	}
+}
+
+func TestUpdate_CreatesAndPersistsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+
+	if err := s.Update("abc123", func(state *VideoState) {
+		state.PublishedAt = "2021-01-01T00:00:00Z"
+		state.Stages[StageFetched] = StageRecord{}
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	state, ok := s.Get("abc123")
+	if !ok {
+		t.Fatal("expected state for abc123 to exist")
+	}
+	if state.PublishedAt != "2021-01-01T00:00:00Z" {
+		t.Errorf("PublishedAt = %q, want %q", state.PublishedAt, "2021-01-01T00:00:00Z")
+	}
+	if _, ok := state.Stages[StageFetched]; !ok {
+		t.Error("expected StageFetched to be recorded")
+	}
+
+	// Reloading from disk should see the same state.
+	reloaded, err := New(path)
+	if err != nil {
+		t.Fatalf("New returned unexpected error on reload: %v", err)
+	}
+	if _, ok := reloaded.Get("abc123"); !ok {
+		t.Fatal("expected state for abc123 to survive reload from disk")
+	}
+}
+
+func TestGet_UnknownVideoReturnsFalse(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+	if _, ok := s.Get("missing"); ok {
+		t.Error("expected ok=false for a video that was never recorded")
+	}
+}
+
+func TestGet_ReturnsCopyNotSharedState(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+	if err := s.Update("abc123", func(state *VideoState) {
+		state.PublishedAt = "original"
+	}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	state, _ := s.Get("abc123")
+	state.PublishedAt = "mutated"
+
+	again, _ := s.Get("abc123")
+	if again.PublishedAt != "original" {
+		t.Errorf("mutating a Get result leaked into the store: PublishedAt = %q", again.PublishedAt)
+	}
+}
+
+func TestAll_ReturnsEveryRecordedVideo(t *testing.T) {
+	s, err := New(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("New returned unexpected error: %v", err)
+	}
+	if err := s.Update("a", func(state *VideoState) {}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+	if err := s.Update("b", func(state *VideoState) {}); err != nil {
+		t.Fatalf("Update returned unexpected error: %v", err)
+	}
+
+	all := s.All()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 recorded videos, got %d", len(all))
+	}
+	if _, ok := all["a"]; !ok {
+		t.Error("expected video \"a\" in All()")
+	}
+	if _, ok := all["b"]; !ok {
+		t.Error("expected video \"b\" in All()")
+	}
+}
+
+func TestPromptHash_ChangesWithPromptText(t *testing.T) {
+	h1 := PromptHash("summarizer", "validator")
+	h2 := PromptHash("summarizer", "validator")
+	h3 := PromptHash("summarizer", "validator edited")
+
+	if h1 != h2 {
+		t.Errorf("PromptHash is not stable for identical input: %q != %q", h1, h2)
+	}
+	if h1 == h3 {
+		t.Error("expected PromptHash to change when prompt text changes")
+	}
+}